@@ -0,0 +1,96 @@
+package mod
+
+import (
+	"os"
+	"strings"
+)
+
+const (
+	EnvGoLDFlags   = "BP_GO_LDFLAGS"
+	EnvGoTags      = "BP_GO_TAGS"
+	EnvGoBuildmode = "BP_GO_BUILDMODE"
+	EnvGoTrimpath  = "BP_GO_TRIMPATH"
+
+	defaultBuildmode = "pie"
+	defaultTag       = "cloudfoundry"
+)
+
+// BuildConfig is the `go.build` section of buildpack.yml. It controls the flags
+// passed to `go install`.
+type BuildConfig struct {
+	LDFlags       string            `yaml:"ldflags"`
+	Tags          []string          `yaml:"tags"`
+	Buildmode     string            `yaml:"buildmode"`
+	Trimpath      bool              `yaml:"trimpath"`
+	Asmflags      string            `yaml:"asmflags"`
+	Gcflags       string            `yaml:"gcflags"`
+	Env           map[string]string `yaml:"env"`
+	NoDefaultTags bool              `yaml:"noDefaultTags"`
+}
+
+// buildArgs assembles the `go install` build flags from the yaml build config
+// and the BP_GO_* overrides. Env overrides win over yaml. Tags are additive:
+// the `cloudfoundry` default tag is only dropped when the user opts out with
+// `noDefaultTags: true`. The caller is responsible for appending `-mod=vendor`
+// and the targets, both of which must follow these flags on the command line.
+func buildArgs(config BuildConfig) []string {
+	buildmode := config.Buildmode
+	if buildmode == "" {
+		buildmode = defaultBuildmode
+	}
+	if env := os.Getenv(EnvGoBuildmode); env != "" {
+		buildmode = env
+	}
+
+	var tags []string
+	if !config.NoDefaultTags {
+		tags = append(tags, defaultTag)
+	}
+	tags = append(tags, config.Tags...)
+	if env := os.Getenv(EnvGoTags); env != "" {
+		tags = append(tags, strings.Split(env, ",")...)
+	}
+
+	args := []string{"install", "-buildmode", buildmode}
+
+	if len(tags) > 0 {
+		args = append(args, "-tags", strings.Join(tags, ","))
+	}
+
+	ldflags := config.LDFlags
+	if env := os.Getenv(EnvGoLDFlags); env != "" {
+		ldflags = env
+	}
+	if ldflags != "" {
+		args = append(args, "-ldflags", ldflags)
+	}
+
+	trimpath := config.Trimpath
+	if env := os.Getenv(EnvGoTrimpath); env != "" {
+		trimpath = env == "true" || env == "1"
+	}
+	if trimpath {
+		args = append(args, "-trimpath")
+	}
+
+	if config.Asmflags != "" {
+		args = append(args, "-asmflags", config.Asmflags)
+	}
+
+	if config.Gcflags != "" {
+		args = append(args, "-gcflags", config.Gcflags)
+	}
+
+	return args
+}
+
+// configureBuildEnv applies the free-form `go.build.env` map to the runner.
+func (c Contributor) configureBuildEnv(config BuildConfig) error {
+	for name, value := range config.Env {
+		if err := c.runner.SetEnv(name, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package mod
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildArgsDefaults(t *testing.T) {
+	got := buildArgs(BuildConfig{})
+	want := []string{"install", "-buildmode", "pie", "-tags", "cloudfoundry"}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestBuildArgsTagsAreAdditive(t *testing.T) {
+	got := buildArgs(BuildConfig{Tags: []string{"extra"}})
+	want := []string{"install", "-buildmode", "pie", "-tags", "cloudfoundry,extra"}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestBuildArgsNoDefaultTagsDropsCloudfoundry(t *testing.T) {
+	got := buildArgs(BuildConfig{Tags: []string{"extra"}, NoDefaultTags: true})
+	want := []string{"install", "-buildmode", "pie", "-tags", "extra"}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestBuildArgsEnvTagsAppendToYamlTags(t *testing.T) {
+	t.Setenv(EnvGoTags, "fromenv")
+
+	got := buildArgs(BuildConfig{Tags: []string{"fromyaml"}})
+	want := []string{"install", "-buildmode", "pie", "-tags", "cloudfoundry,fromyaml,fromenv"}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestBuildArgsBuildmodeEnvOverridesYaml(t *testing.T) {
+	t.Setenv(EnvGoBuildmode, "exe")
+
+	got := buildArgs(BuildConfig{Buildmode: "pie"})
+	want := []string{"install", "-buildmode", "exe", "-tags", "cloudfoundry"}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestBuildArgsLDFlagsEnvOverridesYaml(t *testing.T) {
+	t.Setenv(EnvGoLDFlags, "-X main.Version=fromenv")
+
+	got := buildArgs(BuildConfig{LDFlags: "-X main.Version=fromyaml"})
+	want := []string{"install", "-buildmode", "pie", "-tags", "cloudfoundry", "-ldflags", "-X main.Version=fromenv"}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+// LDFlags values are passed through as a single argv element, so a value
+// containing spaces needs no shell-style quoting to survive intact.
+func TestBuildArgsLDFlagsWithSpacesIsOneArgument(t *testing.T) {
+	ldflags := "-X main.Version=1.2.3 -s -w"
+
+	got := buildArgs(BuildConfig{LDFlags: ldflags})
+
+	found := false
+	for i, arg := range got {
+		if arg == "-ldflags" && i+1 < len(got) && got[i+1] == ldflags {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Fatalf("expected ldflags value to be preserved as a single argument, got %v", got)
+	}
+}
+
+func TestBuildArgsTrimpathEnvOverridesYaml(t *testing.T) {
+	t.Setenv(EnvGoTrimpath, "true")
+
+	got := buildArgs(BuildConfig{Trimpath: false})
+
+	found := false
+	for _, arg := range got {
+		if arg == "-trimpath" {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Fatalf("expected -trimpath to be set, got %v", got)
+	}
+}
+
+func TestBuildArgsAsmflagsAndGcflags(t *testing.T) {
+	got := buildArgs(BuildConfig{Asmflags: "-asm", Gcflags: "-gc"})
+	want := []string{"install", "-buildmode", "pie", "-tags", "cloudfoundry", "-asmflags", "-asm", "-gcflags", "-gc"}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestConfigureBuildEnvSetsEachVariable(t *testing.T) {
+	runner := newFakeRunner()
+	c := Contributor{runner: runner, logger: fakeLogger{}}
+
+	err := c.configureBuildEnv(BuildConfig{Env: map[string]string{"CGO_ENABLED": "0"}})
+	if err != nil {
+		t.Fatalf("configureBuildEnv: %v", err)
+	}
+
+	if runner.env("CGO_ENABLED") != "0" {
+		t.Fatalf("expected CGO_ENABLED=0, got %q", runner.env("CGO_ENABLED"))
+	}
+}
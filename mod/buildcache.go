@@ -0,0 +1,35 @@
+package mod
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/cloudfoundry/libcfbuildpack/layers"
+)
+
+// ContributeBuildCache points GOCACHE at the dedicated build-cache layer, kept
+// separate from the go-mod layer so that the module download cache and the
+// build (action) cache can be tuned and invalidated independently, mirroring
+// upstream cmd/go's split between its download cache and action cache.
+func (c Contributor) ContributeBuildCache(_ layers.Layer) error {
+	c.logger.Info("Setting GOCACHE")
+	return c.runner.SetEnv("GOCACHE", c.buildCacheLayer.Root)
+}
+
+// buildCacheMetadata keys the build-cache layer off the Go toolchain version
+// and the effective build flag set, so that either changing invalidates only
+// the build cache and leaves downloaded modules alone.
+func (c Contributor) buildCacheMetadata(config Config) (Metadata, error) {
+	version, err := c.invoker.RunWithOutput(context.Background(), "go", c.appRoot, true, "version")
+	if err != nil {
+		return Metadata{}, err
+	}
+
+	flags := strings.Join(buildArgs(config.Go.Build), " ")
+
+	sum := sha256.Sum256([]byte(strings.TrimSpace(version) + "|" + flags))
+
+	return Metadata{Name: BuildCache, Hash: hex.EncodeToString(sum[:])}, nil
+}
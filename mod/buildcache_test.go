@@ -0,0 +1,100 @@
+package mod
+
+import (
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/layers"
+)
+
+func goVersionRunner(version string) *fakeRunner {
+	runner := newFakeRunner()
+	runner.outputFunc = func(bin, dir string, quiet bool, args ...string) (string, error) {
+		return version, nil
+	}
+	return runner
+}
+
+func TestBuildCacheMetadataStableForSameInput(t *testing.T) {
+	runner := goVersionRunner("go version go1.21.0 linux/amd64")
+	c := Contributor{runner: runner, invoker: NewInvoker(runner, "/gopath"), appRoot: "/app", logger: fakeLogger{}}
+
+	first, err := c.buildCacheMetadata(Config{})
+	if err != nil {
+		t.Fatalf("buildCacheMetadata: %v", err)
+	}
+
+	second, err := c.buildCacheMetadata(Config{})
+	if err != nil {
+		t.Fatalf("buildCacheMetadata: %v", err)
+	}
+
+	if first.Hash != second.Hash {
+		t.Fatalf("expected stable hash for identical input, got %q and %q", first.Hash, second.Hash)
+	}
+
+	if name, _ := first.Identity(); name != BuildCache {
+		t.Fatalf("expected metadata name %q, got %q", BuildCache, name)
+	}
+}
+
+func TestBuildCacheMetadataChangesWithToolchainVersion(t *testing.T) {
+	oldRunner := goVersionRunner("go version go1.20.0 linux/amd64")
+	oldContrib := Contributor{runner: oldRunner, invoker: NewInvoker(oldRunner, "/gopath"), appRoot: "/app", logger: fakeLogger{}}
+
+	newRunner := goVersionRunner("go version go1.21.0 linux/amd64")
+	newContrib := Contributor{runner: newRunner, invoker: NewInvoker(newRunner, "/gopath"), appRoot: "/app", logger: fakeLogger{}}
+
+	oldMeta, err := oldContrib.buildCacheMetadata(Config{})
+	if err != nil {
+		t.Fatalf("buildCacheMetadata: %v", err)
+	}
+
+	newMeta, err := newContrib.buildCacheMetadata(Config{})
+	if err != nil {
+		t.Fatalf("buildCacheMetadata: %v", err)
+	}
+
+	if oldMeta.Hash == newMeta.Hash {
+		t.Fatalf("expected a toolchain version change to invalidate the build cache key")
+	}
+}
+
+func TestBuildCacheMetadataChangesWithBuildFlags(t *testing.T) {
+	runner := goVersionRunner("go version go1.21.0 linux/amd64")
+	c := Contributor{runner: runner, invoker: NewInvoker(runner, "/gopath"), appRoot: "/app", logger: fakeLogger{}}
+
+	var withoutFlags Config
+	withFlags := Config{}
+	withFlags.Go.Build = BuildConfig{Tags: []string{"extra"}}
+
+	base, err := c.buildCacheMetadata(withoutFlags)
+	if err != nil {
+		t.Fatalf("buildCacheMetadata: %v", err)
+	}
+
+	changed, err := c.buildCacheMetadata(withFlags)
+	if err != nil {
+		t.Fatalf("buildCacheMetadata: %v", err)
+	}
+
+	if base.Hash == changed.Hash {
+		t.Fatalf("expected a build flag change to invalidate the build cache key independently of module downloads")
+	}
+}
+
+func TestContributeBuildCacheSetsGocache(t *testing.T) {
+	runner := newFakeRunner()
+	c := Contributor{
+		runner:          runner,
+		buildCacheLayer: newTestLayer("/layers/go-build-cache"),
+		logger:          fakeLogger{},
+	}
+
+	if err := c.ContributeBuildCache(layers.Layer{}); err != nil {
+		t.Fatalf("ContributeBuildCache: %v", err)
+	}
+
+	if runner.env("GOCACHE") != "/layers/go-build-cache" {
+		t.Fatalf("expected GOCACHE to point at the build-cache layer, got %q", runner.env("GOCACHE"))
+	}
+}
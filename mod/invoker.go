@@ -0,0 +1,146 @@
+package mod
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// invocationLocks holds one mutex per GOPATH/module cache directory, shared
+// across every Invoker that targets it, so concurrent `go` commands touching
+// the same module cache (e.g. parallel layer contributions) serialize instead
+// of racing, matching the mutex gopls' gocommand invoker keeps around `go`
+// commands on a shared go.mod.
+var (
+	invocationLocksMu sync.Mutex
+	invocationLocks   = map[string]*sync.Mutex{}
+)
+
+func lockFor(gopath string) *sync.Mutex {
+	invocationLocksMu.Lock()
+	defer invocationLocksMu.Unlock()
+
+	mu, ok := invocationLocks[gopath]
+	if !ok {
+		mu = &sync.Mutex{}
+		invocationLocks[gopath] = mu
+	}
+
+	return mu
+}
+
+// Invoker serializes `go` invocations against a single GOPATH, propagates
+// context cancellation, and classifies failures into a GoCommandError.
+type Invoker struct {
+	runner Runner
+	gopath string
+}
+
+func NewInvoker(runner Runner, gopath string) *Invoker {
+	return &Invoker{runner: runner, gopath: gopath}
+}
+
+// Run streams the command's output live via the underlying Runner.Run, the
+// same as calling the runner directly, just serialized and classified.
+func (i *Invoker) Run(ctx context.Context, bin, dir string, quiet bool, args ...string) error {
+	_, err := i.run(ctx, args, func() (string, error) {
+		return "", i.runner.Run(bin, dir, quiet, args...)
+	})
+	return err
+}
+
+// RunWithOutput buffers the command's combined output via the underlying
+// Runner.RunWithOutput and returns it once the command exits.
+func (i *Invoker) RunWithOutput(ctx context.Context, bin, dir string, quiet bool, args ...string) (string, error) {
+	return i.run(ctx, args, func() (string, error) {
+		return i.runner.RunWithOutput(bin, dir, quiet, args...)
+	})
+}
+
+// run serializes the invocation under the GOPATH's lock and blocks until the
+// underlying command has actually finished, holding the lock the whole time.
+// The Runner interface has no way to kill an in-flight command, so cancelling
+// ctx only pre-empts commands that have not started yet; once a command is
+// running, ctx is just consulted to decide which error to report.
+func (i *Invoker) run(ctx context.Context, args []string, call func() (string, error)) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	mu := lockFor(i.gopath)
+	mu.Lock()
+	defer mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	output, err := call()
+
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return output, ctxErr
+	}
+
+	if err != nil {
+		return output, newGoCommandError(args, output, err)
+	}
+
+	return output, nil
+}
+
+// GoCommandError classifies a failed `go` invocation: the package it was
+// operating on (best-effort, the last non-flag argument), the process exit
+// code, and a truncated tail of its output for display.
+type GoCommandError struct {
+	Package  string
+	ExitCode int
+	Output   string
+}
+
+func (e *GoCommandError) Error() string {
+	return fmt.Sprintf("go command failed (package %s, exit code %d):\n%s", e.Package, e.ExitCode, e.Output)
+}
+
+const maxGoCommandErrorOutput = 4096
+
+// packageArgSubcommands are the `go` subcommands whose trailing non-flag
+// argument is a package path. For every other subcommand (`mod verify`,
+// `list -m`, `version`, ...) that argument means something else entirely, so
+// GoCommandError.Package is left blank rather than reporting a misleading
+// value.
+var packageArgSubcommands = map[string]bool{
+	"install": true,
+	"build":   true,
+}
+
+func newGoCommandError(args []string, output string, err error) *GoCommandError {
+	pkg := ""
+	if len(args) > 0 && packageArgSubcommands[args[0]] {
+		for _, arg := range args[1:] {
+			if !strings.HasPrefix(arg, "-") {
+				pkg = arg
+			}
+		}
+	}
+
+	exitCode := -1
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		exitCode = exitErr.ExitCode()
+	}
+
+	return &GoCommandError{
+		Package:  pkg,
+		ExitCode: exitCode,
+		Output:   truncateTail(output, maxGoCommandErrorOutput),
+	}
+}
+
+func truncateTail(output string, max int) string {
+	if len(output) <= max {
+		return output
+	}
+
+	return output[len(output)-max:]
+}
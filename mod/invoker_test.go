@@ -0,0 +1,171 @@
+package mod
+
+import (
+	"context"
+	"os/exec"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestInvokerSerializesConcurrentCallers(t *testing.T) {
+	var mu sync.Mutex
+	var intervals [][2]time.Time
+
+	runner := newFakeRunner()
+	runner.outputFunc = func(bin, dir string, quiet bool, args ...string) (string, error) {
+		start := time.Now()
+		time.Sleep(20 * time.Millisecond)
+		end := time.Now()
+
+		mu.Lock()
+		intervals = append(intervals, [2]time.Time{start, end})
+		mu.Unlock()
+
+		return "", nil
+	}
+
+	invoker := NewInvoker(runner, "/shared/gopath")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := invoker.RunWithOutput(context.Background(), "go", "/app", false, "list", "-m"); err != nil {
+				t.Errorf("RunWithOutput: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(intervals) != 4 {
+		t.Fatalf("expected 4 recorded calls, got %d", len(intervals))
+	}
+
+	for i := 0; i < len(intervals); i++ {
+		for j := i + 1; j < len(intervals); j++ {
+			a, b := intervals[i], intervals[j]
+			overlap := a[0].Before(b[1]) && b[0].Before(a[1])
+			if overlap {
+				t.Fatalf("expected calls to be serialized, but %v and %v overlapped", a, b)
+			}
+		}
+	}
+}
+
+func TestInvokerDoesNotSerializeAcrossDifferentGopaths(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 2)
+
+	runner := newFakeRunner()
+	runner.outputFunc = func(bin, dir string, quiet bool, args ...string) (string, error) {
+		started <- struct{}{}
+		<-release
+		return "", nil
+	}
+
+	invokerA := NewInvoker(runner, "/gopath/a")
+	invokerB := NewInvoker(runner, "/gopath/b")
+
+	done := make(chan struct{}, 2)
+	go func() {
+		invokerA.Run(context.Background(), "go", "/app", false, "list", "-m")
+		done <- struct{}{}
+	}()
+	go func() {
+		invokerB.Run(context.Background(), "go", "/app", false, "list", "-m")
+		done <- struct{}{}
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for first call to start")
+	}
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("expected a second, independent gopath to start without waiting on the first")
+	}
+
+	close(release)
+	<-done
+	<-done
+}
+
+func TestInvokerReturnsImmediatelyForAlreadyCancelledContext(t *testing.T) {
+	runner := newFakeRunner()
+	invoker := NewInvoker(runner, "/gopath")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := invoker.RunWithOutput(ctx, "go", "/app", false, "list", "-m")
+	if err == nil {
+		t.Fatalf("expected a cancelled context to short-circuit the call")
+	}
+	if runner.callCount() != 0 {
+		t.Fatalf("expected the underlying runner not to be invoked for an already-cancelled context")
+	}
+}
+
+func TestGoCommandErrorClassifiesInstallFailures(t *testing.T) {
+	exitErr := runAndCaptureExitError(t)
+
+	runner := newFakeRunner()
+	runner.outputFunc = func(bin, dir string, quiet bool, args ...string) (string, error) {
+		return "build failed", exitErr
+	}
+
+	invoker := NewInvoker(runner, "/gopath")
+
+	_, err := invoker.RunWithOutput(context.Background(), "go", "/app", false, "install", "-buildmode", "pie", "github.com/foo/cmd/web")
+	cmdErr, ok := err.(*GoCommandError)
+	if !ok {
+		t.Fatalf("expected *GoCommandError, got %T: %v", err, err)
+	}
+
+	if cmdErr.Package != "github.com/foo/cmd/web" {
+		t.Fatalf("expected package to be the install target, got %q", cmdErr.Package)
+	}
+	if cmdErr.ExitCode != 1 {
+		t.Fatalf("expected exit code 1, got %d", cmdErr.ExitCode)
+	}
+}
+
+func TestGoCommandErrorLeavesPackageBlankForNonInstallSubcommands(t *testing.T) {
+	exitErr := runAndCaptureExitError(t)
+
+	runner := newFakeRunner()
+	runner.outputFunc = func(bin, dir string, quiet bool, args ...string) (string, error) {
+		return "example.com/foo@v1.0.0: checksum mismatch", exitErr
+	}
+
+	invoker := NewInvoker(runner, "/gopath")
+
+	_, err := invoker.RunWithOutput(context.Background(), "go", "/app", false, "mod", "verify")
+	cmdErr, ok := err.(*GoCommandError)
+	if !ok {
+		t.Fatalf("expected *GoCommandError, got %T: %v", err, err)
+	}
+
+	if cmdErr.Package != "" {
+		t.Fatalf("expected no package guess for `go mod verify`, got %q", cmdErr.Package)
+	}
+}
+
+// runAndCaptureExitError runs a failing subprocess to obtain a real
+// *exec.ExitError, the same concrete error type Runner implementations
+// surface for a nonzero exit.
+func runAndCaptureExitError(t *testing.T) *exec.ExitError {
+	t.Helper()
+
+	err := exec.Command("false").Run()
+	exitErr, ok := err.(*exec.ExitError)
+	if !ok {
+		t.Fatalf("expected `false` to fail with *exec.ExitError, got %T: %v", err, err)
+	}
+
+	return exitErr
+}
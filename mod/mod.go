@@ -1,6 +1,7 @@
 package mod
 
 import (
+	"context"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -15,6 +16,7 @@ import (
 
 const (
 	Dependency = "go-mod"
+	BuildCache = "go-build-cache"
 	Launch     = "app-binary"
 )
 
@@ -46,28 +48,36 @@ func (m Metadata) Identity() (name string, version string) {
 }
 
 type Contributor struct {
-	goModMetadata MetadataInterface
-	goBinMetadata MetadataInterface
-	goModLayer    layers.Layer
-	launchLayer   layers.Layer
-	runner        Runner
-	appRoot       string
-	logger        Logger
-	launch        layers.Layers
-	appName       string
-	targets       []string
+	goModMetadata        MetadataInterface
+	goBinMetadata        MetadataInterface
+	goBuildCacheMetadata MetadataInterface
+	goModLayer           layers.Layer
+	buildCacheLayer      layers.Layer
+	launchLayer          layers.Layer
+	runner               Runner
+	invoker              *Invoker
+	appRoot              string
+	logger               Logger
+	launch               layers.Layers
+	appName              string
+	targets              []string
+	binaries             []binary
 }
 
 func NewContributor(context build.Build, runner Runner) Contributor {
+	goModLayer := context.Layers.Layer(Dependency)
+
 	return Contributor{
-		goModLayer:    context.Layers.Layer(Dependency),
-		launchLayer:   context.Layers.Layer(Launch),
-		goModMetadata: nil,
-		goBinMetadata: nil,
-		runner:        runner,
-		appRoot:       context.Application.Root,
-		logger:        context.Logger,
-		launch:        context.Layers,
+		goModLayer:      goModLayer,
+		buildCacheLayer: context.Layers.Layer(BuildCache),
+		launchLayer:     context.Layers.Layer(Launch),
+		goModMetadata:   nil,
+		goBinMetadata:   nil,
+		runner:          runner,
+		invoker:         NewInvoker(runner, goModLayer.Root),
+		appRoot:         context.Application.Root,
+		logger:          context.Logger,
+		launch:          context.Layers,
 	}
 }
 
@@ -78,6 +88,23 @@ func (c Contributor) Contribute() error {
 	}
 	c.targets = targets
 
+	config, err := c.loadConfig()
+	if err != nil {
+		return err
+	}
+
+	cacheMetadata, err := c.buildCacheMetadata(config)
+	if err != nil {
+		return err
+	}
+	c.goBuildCacheMetadata = cacheMetadata
+
+	// GOCACHE must be set before ContributeGoModules runs `go install`, so the
+	// build-cache layer has to be contributed first.
+	if err := c.buildCacheLayer.Contribute(c.goBuildCacheMetadata, c.ContributeBuildCache, []layers.Flag{layers.Cache}...); err != nil {
+		return err
+	}
+
 	if err := c.goModLayer.Contribute(c.goModMetadata, c.ContributeGoModules, []layers.Flag{layers.Cache}...); err != nil {
 		return err
 	}
@@ -99,37 +126,69 @@ func (c Contributor) ContributeGoModules(_ layers.Layer) error {
 		return err
 	}
 
-	args := []string{"install", "-buildmode", "pie", "-tags", "cloudfoundry"}
+	config, err := c.loadConfig()
+	if err != nil {
+		return err
+	}
 
-	if exists, err := helper.FileExists(filepath.Join(c.appRoot, "vendor")); err != nil {
+	netrcPath, err := c.configurePrivateModules(config)
+	if err != nil {
+		return err
+	}
+	if netrcPath != "" {
+		defer os.Remove(netrcPath)
+	}
+
+	if err := c.configureBuildEnv(config.Go.Build); err != nil {
+		return err
+	}
+
+	if exists, err := helper.FileExists(filepath.Join(c.appRoot, "go.sum")); err != nil {
 		return err
 	} else if exists {
-		args = append(args, "-mod=vendor")
+		if err := c.verifyModules(); err != nil {
+			return err
+		}
 	}
 
-	for _, target := range c.targets {
-		args = append(args, target)
+	vendored, err := c.ensureVendor(config)
+	if err != nil {
+		return err
 	}
 
+	args := buildArgs(config.Go.Build)
+
+	if vendored {
+		args = append(args, "-mod=vendor")
+	}
+
+	args = append(args, c.targets...)
+
 	c.logger.Info("Running `go install`")
-	if err := c.runner.Run("go", c.appRoot, false, args...); err != nil {
+	if err := c.invoker.Run(context.Background(), "go", c.appRoot, false, args...); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (c Contributor) ContributeBinLayer(binLayer layers.Layer) error {
+func (c Contributor) ContributeBinLayer(_ layers.Layer) error {
 	c.logger.Info("Contributing app binary layer")
 
-	oldBinPath := filepath.Join(c.goModLayer.Root, "bin", c.appName)
-	newBinPath := filepath.Join(c.launchLayer.Root, c.appName)
-
 	if err := os.MkdirAll(c.launchLayer.Root, os.ModePerm); err != nil {
 		return err
 	}
 
-	return os.Rename(oldBinPath, newBinPath)
+	for _, b := range c.binaries {
+		oldBinPath := filepath.Join(c.goModLayer.Root, "bin", b.name)
+		newBinPath := filepath.Join(c.launchLayer.Root, b.name)
+
+		if err := os.Rename(oldBinPath, newBinPath); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func (c Contributor) Cleanup() error {
@@ -151,28 +210,89 @@ type Module struct {
 	Path string `json:"Path"`
 }
 
+// binary is one binary produced by `go install`: the import path that built it
+// (empty for a single, target-less module build) and the file name `go
+// install` gave it under GOPATH/bin.
+type binary struct {
+	target string
+	name   string
+}
+
 func (c *Contributor) setAppName() error {
 	if len(c.targets) != 0 {
-		targetSegments := strings.Split(c.targets[0], "/")
-		appName := targetSegments[len(targetSegments)-1]
-		c.appName = appName
+		binaries := make([]binary, 0, len(c.targets))
+		for _, target := range c.targets {
+			binaries = append(binaries, binary{target: target, name: lastSegment(target)})
+		}
+		c.binaries = binaries
 	} else {
-		output, err := c.runner.RunWithOutput("go", c.appRoot, false, "list", "-m")
+		output, err := c.invoker.RunWithOutput(context.Background(), "go", c.appRoot, false, "list", "-m")
 		if err != nil {
 			return err
 		}
 
-		c.appName = parseAppNameFromOutput(output)
+		c.binaries = []binary{{name: parseAppNameFromOutput(output)}}
 	}
 
+	c.appName = c.binaries[0].name
+
 	return nil
 }
 
+func lastSegment(path string) string {
+	segments := strings.Split(path, "/")
+	return segments[len(segments)-1]
+}
+
+// setStartCommand writes one launch process per built binary. The first
+// binary is the `web` default unless go.processes marks a different target as
+// default; every other binary defaults to its own binary name, so distinct
+// targets get distinct, valid process types without any configuration.
 func (c Contributor) setStartCommand() error {
 	c.logger.Info("contributing start command")
-	launchPath := filepath.Join(c.launchLayer.Root, c.appName)
 
-	return c.launch.WriteApplicationMetadata(layers.Metadata{Processes: []layers.Process{{"web", launchPath}}})
+	config, err := c.loadConfig()
+	if err != nil {
+		return err
+	}
+
+	return c.launch.WriteApplicationMetadata(layers.Metadata{Processes: c.buildProcesses(config)})
+}
+
+func (c Contributor) buildProcesses(config Config) []layers.Process {
+	defaultIndex := 0
+	for i, b := range c.binaries {
+		if override, ok := config.Go.Processes[b.target]; ok && override.Default {
+			defaultIndex = i
+		}
+	}
+
+	processes := make([]layers.Process, 0, len(c.binaries))
+	for i, b := range c.binaries {
+		command := filepath.Join(c.launchLayer.Root, b.name)
+		var args []string
+
+		processType := b.name
+		if i == defaultIndex {
+			processType = "web"
+		}
+
+		if override, ok := config.Go.Processes[b.target]; ok {
+			if override.Type != "" {
+				processType = override.Type
+			}
+
+			if override.Command != "" {
+				command = override.Command
+			}
+
+			args = override.Args
+		}
+
+		processes = append(processes, layers.Process{Type: processType, Command: command, Args: args})
+	}
+
+	return processes
 }
 
 func parseAppNameFromOutput(output string) string {
@@ -182,33 +302,61 @@ func parseAppNameFromOutput(output string) string {
 }
 
 func sanitizeOutput(output string) string {
-	lines := strings.Split(output, "\n")
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
 	return lines[len(lines)-1]
 }
 
 type Config struct {
 	Go struct {
-		Targets []string `yaml:"targets"`
+		Targets   []string                 `yaml:"targets"`
+		Private   PrivateConfig            `yaml:"private"`
+		Build     BuildConfig              `yaml:"build"`
+		Vendor    string                   `yaml:"vendor"`
+		Processes map[string]ProcessConfig `yaml:"processes"`
 	} `yaml:"go"`
 }
 
+// ProcessConfig customizes the launch process generated for one build target.
+// Type is the CNB process type (e.g. "web", "worker", "task") and doubles as
+// the process's identifier; there is no separate "name" concept.
+type ProcessConfig struct {
+	Command string   `yaml:"command"`
+	Args    []string `yaml:"args"`
+	Type    string   `yaml:"type"`
+	Default bool     `yaml:"default"`
+}
+
+// loadConfig reads and parses buildpack.yml, if present. A missing file is not
+// an error; callers receive a zero-value Config.
+func (c Contributor) loadConfig() (Config, error) {
+	config := Config{}
+
+	configPath := filepath.Join(c.appRoot, "buildpack.yml")
+	if _, err := os.Stat(configPath); err != nil {
+		return config, nil
+	}
+
+	yamlFile, err := ioutil.ReadFile(configPath)
+	if err != nil {
+		return Config{}, err
+	}
+
+	if err := yaml.Unmarshal(yamlFile, &config); err != nil {
+		return Config{}, err
+	}
+
+	return config, nil
+}
+
 func (c Contributor) determineTargets() ([]string, error) {
 	if buildTarget := os.Getenv("BP_GO_TARGETS"); buildTarget != "" {
 		targets := strings.Split(buildTarget, ":")
 		return targets, nil
 	}
 
-	configPath := filepath.Join(c.appRoot, "buildpack.yml")
-	config := Config{}
-	if _, err := os.Stat(configPath); err == nil {
-		yamlFile, err := ioutil.ReadFile(configPath)
-		if err != nil {
-			return nil, err
-		}
-		err = yaml.Unmarshal(yamlFile, &config)
-		if err != nil {
-			return nil, err
-		}
+	config, err := c.loadConfig()
+	if err != nil {
+		return nil, err
 	}
 
 	if len(config.Go.Targets) < 1 {
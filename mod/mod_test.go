@@ -0,0 +1,196 @@
+package mod
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/layers"
+)
+
+func processFields(p layers.Process) (string, string) {
+	return p.Type, p.Command
+}
+
+func TestSetAppNameSingleTargetUsesModuleName(t *testing.T) {
+	runner := newFakeRunner()
+	runner.outputFunc = func(bin, dir string, quiet bool, args ...string) (string, error) {
+		return "github.com/foo/bar\n", nil
+	}
+
+	c := &Contributor{runner: runner, invoker: NewInvoker(runner, "/gopath"), appRoot: "/app", logger: fakeLogger{}}
+
+	if err := c.setAppName(); err != nil {
+		t.Fatalf("setAppName: %v", err)
+	}
+
+	if c.appName != "bar" {
+		t.Fatalf("expected appName %q, got %q", "bar", c.appName)
+	}
+	if len(c.binaries) != 1 || c.binaries[0].name != "bar" || c.binaries[0].target != "" {
+		t.Fatalf("unexpected binaries for single-target build: %+v", c.binaries)
+	}
+}
+
+func TestSetAppNameMultiTargetFanOut(t *testing.T) {
+	runner := newFakeRunner()
+	c := &Contributor{
+		runner:  runner,
+		invoker: NewInvoker(runner, "/gopath"),
+		appRoot: "/app",
+		logger:  fakeLogger{},
+		targets: []string{"github.com/foo/cmd/web", "github.com/foo/cmd/worker"},
+	}
+
+	if err := c.setAppName(); err != nil {
+		t.Fatalf("setAppName: %v", err)
+	}
+
+	want := []binary{
+		{target: "github.com/foo/cmd/web", name: "web"},
+		{target: "github.com/foo/cmd/worker", name: "worker"},
+	}
+	if !reflect.DeepEqual(c.binaries, want) {
+		t.Fatalf("got %+v, want %+v", c.binaries, want)
+	}
+	if c.appName != "web" {
+		t.Fatalf("expected appName to default to the first target, got %q", c.appName)
+	}
+}
+
+func TestContributeBinLayerMovesEveryBinary(t *testing.T) {
+	goModLayer := t.TempDir()
+	launchLayer := t.TempDir()
+
+	binDir := filepath.Join(goModLayer, "bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	for _, name := range []string{"web", "worker"} {
+		if err := os.WriteFile(filepath.Join(binDir, name), []byte("binary"), 0755); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+	}
+
+	c := Contributor{
+		goModLayer:  newTestLayer(goModLayer),
+		launchLayer: newTestLayer(launchLayer),
+		logger:      fakeLogger{},
+		binaries: []binary{
+			{target: "github.com/foo/cmd/web", name: "web"},
+			{target: "github.com/foo/cmd/worker", name: "worker"},
+		},
+	}
+
+	if err := c.ContributeBinLayer(layers.Layer{}); err != nil {
+		t.Fatalf("ContributeBinLayer: %v", err)
+	}
+
+	for _, name := range []string{"web", "worker"} {
+		if _, err := os.Stat(filepath.Join(launchLayer, name)); err != nil {
+			t.Fatalf("expected %s to be moved into the launch layer: %v", name, err)
+		}
+		if _, err := os.Stat(filepath.Join(binDir, name)); !os.IsNotExist(err) {
+			t.Fatalf("expected %s to be removed from GOPATH/bin, stat err: %v", name, err)
+		}
+	}
+}
+
+func TestBuildProcessesSingleTargetDefaultsToWeb(t *testing.T) {
+	c := Contributor{
+		launchLayer: newTestLayer("/layers/app-binary"),
+		binaries:    []binary{{name: "bar"}},
+	}
+
+	processes := c.buildProcesses(Config{})
+	if len(processes) != 1 {
+		t.Fatalf("expected 1 process, got %d", len(processes))
+	}
+
+	processType, command := processFields(processes[0])
+	if processType != "web" {
+		t.Fatalf("expected single-target process type %q, got %q", "web", processType)
+	}
+	if command != filepath.Join("/layers/app-binary", "bar") {
+		t.Fatalf("unexpected command %q", command)
+	}
+}
+
+func TestBuildProcessesMultiTargetFanOut(t *testing.T) {
+	c := Contributor{
+		launchLayer: newTestLayer("/layers/app-binary"),
+		binaries: []binary{
+			{target: "github.com/foo/cmd/web", name: "web"},
+			{target: "github.com/foo/cmd/worker", name: "worker"},
+			{target: "github.com/foo/cmd/scheduler", name: "scheduler"},
+		},
+	}
+
+	processes := c.buildProcesses(Config{})
+	if len(processes) != 3 {
+		t.Fatalf("expected 3 processes, got %d", len(processes))
+	}
+
+	wantTypes := []string{"web", "worker", "scheduler"}
+	seen := map[string]bool{}
+	for i, process := range processes {
+		processType, _ := processFields(process)
+		if processType != wantTypes[i] {
+			t.Fatalf("process %d: expected type %q, got %q", i, wantTypes[i], processType)
+		}
+		if seen[processType] {
+			t.Fatalf("process %d: type %q collides with an earlier process, types must be unique", i, processType)
+		}
+		seen[processType] = true
+	}
+}
+
+func TestBuildProcessesHonorsConfiguredDefaultAndType(t *testing.T) {
+	c := Contributor{
+		launchLayer: newTestLayer("/layers/app-binary"),
+		binaries: []binary{
+			{target: "github.com/foo/cmd/web", name: "web"},
+			{target: "github.com/foo/cmd/cron", name: "cron"},
+		},
+	}
+
+	config := Config{}
+	config.Go.Processes = map[string]ProcessConfig{
+		"github.com/foo/cmd/cron": {Default: true, Type: "task"},
+	}
+
+	processes := c.buildProcesses(config)
+
+	webType, _ := processFields(processes[0])
+	if webType != "web" {
+		t.Fatalf("expected the non-default binary to fall back to its own binary name, got %q", webType)
+	}
+
+	cronType, _ := processFields(processes[1])
+	if cronType != "task" {
+		t.Fatalf("expected explicit type to win over the implied web default, got %q", cronType)
+	}
+}
+
+func TestBuildProcessesCommandAndArgsOverride(t *testing.T) {
+	c := Contributor{
+		launchLayer: newTestLayer("/layers/app-binary"),
+		binaries:    []binary{{target: "github.com/foo/cmd/web", name: "web"}},
+	}
+
+	config := Config{}
+	config.Go.Processes = map[string]ProcessConfig{
+		"github.com/foo/cmd/web": {Command: "/layers/app-binary/web", Args: []string{"--port=8080"}},
+	}
+
+	processes := c.buildProcesses(config)
+	_, command := processFields(processes[0])
+
+	if command != "/layers/app-binary/web" {
+		t.Fatalf("got command %q, want %q", command, "/layers/app-binary/web")
+	}
+	if !reflect.DeepEqual(processes[0].Args, []string{"--port=8080"}) {
+		t.Fatalf("got args %v, want %v", processes[0].Args, []string{"--port=8080"})
+	}
+}
@@ -0,0 +1,176 @@
+package mod
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// EnvGoPrivateHosts lets the user append to GOPRIVATE without editing buildpack.yml.
+	EnvGoPrivateHosts = "BP_GO_PRIVATE_HOSTS"
+
+	// EnvServiceBindingsRoot points at the directory of CNB service bindings, one
+	// sub-directory per binding name.
+	EnvServiceBindingsRoot = "SERVICE_BINDINGS"
+
+	DefaultServiceBindingsRoot = "/platform/bindings"
+)
+
+// PrivateConfig is the `go.private` section of buildpack.yml. It configures GOPRIVATE
+// and the netrc credentials used to fetch modules from private VCS hosts.
+type PrivateConfig struct {
+	Hosts    []string `yaml:"hosts"`
+	Bindings []string `yaml:"bindings"`
+}
+
+// netrcEntry is a single `machine` stanza in a netrc file.
+type netrcEntry struct {
+	Machine  string
+	Login    string
+	Password string
+}
+
+// configurePrivateModules wires up GOPRIVATE/GONOSUMCHECK/GONOSUMDB and, if any
+// binding names are configured, materializes a ~/.netrc so `go install` can
+// authenticate against private hosts. It returns the netrc path it wrote, if
+// any, so the caller can remove it once `go install` no longer needs it --
+// the credentials it carries must not persist past the build step.
+func (c Contributor) configurePrivateModules(config Config) (string, error) {
+	hosts := config.Go.Private.Hosts
+	if envHosts := os.Getenv(EnvGoPrivateHosts); envHosts != "" {
+		hosts = append(hosts, strings.Split(envHosts, ",")...)
+	}
+
+	if len(hosts) == 0 && len(config.Go.Private.Bindings) == 0 {
+		return "", nil
+	}
+
+	if len(hosts) > 0 {
+		private := strings.Join(hosts, ",")
+
+		c.logger.Info("Setting GOPRIVATE to %s", private)
+		if err := c.runner.SetEnv("GOPRIVATE", private); err != nil {
+			return "", err
+		}
+		if err := c.runner.SetEnv("GONOSUMCHECK", "1"); err != nil {
+			return "", err
+		}
+		if err := c.runner.SetEnv("GONOSUMDB", private); err != nil {
+			return "", err
+		}
+	}
+
+	if len(config.Go.Private.Bindings) == 0 {
+		return "", nil
+	}
+
+	entries, err := c.collectNetrcEntries(config.Go.Private.Bindings)
+	if err != nil {
+		return "", err
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	netrcPath := filepath.Join(home, ".netrc")
+
+	c.logger.Info("Writing %s", netrcPath)
+	if err := writeNetrc(netrcPath, entries); err != nil {
+		return "", err
+	}
+
+	return netrcPath, nil
+}
+
+// collectNetrcEntries reads one netrc entry per named binding out of the CNB
+// service-binding directory ($SERVICE_BINDINGS/<name>/{machine,login,password}).
+// A binding that exposes a token instead of a login/password pair (the
+// GIT_TOKEN convention) is synthesized into a single-secret netrc entry.
+func (c Contributor) collectNetrcEntries(bindings []string) ([]netrcEntry, error) {
+	root := os.Getenv(EnvServiceBindingsRoot)
+	if root == "" {
+		root = DefaultServiceBindingsRoot
+	}
+
+	var entries []netrcEntry
+	for _, name := range bindings {
+		entry, err := readNetrcBinding(filepath.Join(root, name))
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func readNetrcBinding(bindingDir string) (netrcEntry, error) {
+	machine, err := readBindingFile(bindingDir, "machine")
+	if err != nil {
+		return netrcEntry{}, err
+	}
+
+	if token, err := readBindingFileIfExists(bindingDir, "token"); err != nil {
+		return netrcEntry{}, err
+	} else if token != "" {
+		login, err := readBindingFileIfExists(bindingDir, "login")
+		if err != nil {
+			return netrcEntry{}, err
+		}
+		if login == "" {
+			login = "x-access-token"
+		}
+		return netrcEntry{Machine: machine, Login: login, Password: token}, nil
+	}
+
+	login, err := readBindingFile(bindingDir, "login")
+	if err != nil {
+		return netrcEntry{}, err
+	}
+
+	password, err := readBindingFile(bindingDir, "password")
+	if err != nil {
+		return netrcEntry{}, err
+	}
+
+	return netrcEntry{Machine: machine, Login: login, Password: password}, nil
+}
+
+func readBindingFile(bindingDir, name string) (string, error) {
+	contents, err := ioutil.ReadFile(filepath.Join(bindingDir, name))
+	if err != nil {
+		return "", fmt.Errorf("unable to read %s binding %q: %w", name, bindingDir, err)
+	}
+
+	return strings.TrimSpace(string(contents)), nil
+}
+
+func readBindingFileIfExists(bindingDir, name string) (string, error) {
+	path := filepath.Join(bindingDir, name)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return "", nil
+	}
+
+	return readBindingFile(bindingDir, name)
+}
+
+// writeNetrc renders entries as a netrc file at path, chmod'd 0600 so that the
+// credentials it carries are not world-readable.
+func writeNetrc(path string, entries []netrcEntry) error {
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		buf.WriteString(fmt.Sprintf("machine %s\nlogin %s\npassword %s\n\n", entry.Machine, entry.Login, entry.Password))
+	}
+
+	if err := ioutil.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		return err
+	}
+
+	return os.Chmod(path, 0600)
+}
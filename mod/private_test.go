@@ -0,0 +1,204 @@
+package mod
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeBindingFile(t *testing.T, dir, name, contents string) {
+	t.Helper()
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("MkdirAll(%s): %v", dir, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+		t.Fatalf("WriteFile(%s/%s): %v", dir, name, err)
+	}
+}
+
+func TestReadNetrcBindingLoginPassword(t *testing.T) {
+	dir := t.TempDir()
+	writeBindingFile(t, dir, "machine", "github.com\n")
+	writeBindingFile(t, dir, "login", "git\n")
+	writeBindingFile(t, dir, "password", "s3cr3t\n")
+
+	entry, err := readNetrcBinding(dir)
+	if err != nil {
+		t.Fatalf("readNetrcBinding: %v", err)
+	}
+
+	if entry.Machine != "github.com" || entry.Login != "git" || entry.Password != "s3cr3t" {
+		t.Fatalf("unexpected entry: %+v", entry)
+	}
+}
+
+func TestReadNetrcBindingToken(t *testing.T) {
+	dir := t.TempDir()
+	writeBindingFile(t, dir, "machine", "gitlab.com")
+	writeBindingFile(t, dir, "token", "tok_123")
+
+	entry, err := readNetrcBinding(dir)
+	if err != nil {
+		t.Fatalf("readNetrcBinding: %v", err)
+	}
+
+	if entry.Login != "x-access-token" || entry.Password != "tok_123" {
+		t.Fatalf("unexpected entry for token binding: %+v", entry)
+	}
+}
+
+func TestReadNetrcBindingTokenWithExplicitLogin(t *testing.T) {
+	dir := t.TempDir()
+	writeBindingFile(t, dir, "machine", "bitbucket.org")
+	writeBindingFile(t, dir, "token", "tok_456")
+	writeBindingFile(t, dir, "login", "svc-account")
+
+	entry, err := readNetrcBinding(dir)
+	if err != nil {
+		t.Fatalf("readNetrcBinding: %v", err)
+	}
+
+	if entry.Login != "svc-account" || entry.Password != "tok_456" {
+		t.Fatalf("explicit login should win over the token default: %+v", entry)
+	}
+}
+
+func TestWriteNetrcPermissionsAndContent(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".netrc")
+
+	entries := []netrcEntry{
+		{Machine: "github.com", Login: "git", Password: "one"},
+		{Machine: "gitlab.com", Login: "x-access-token", Password: "two"},
+	}
+
+	if err := writeNetrc(path, entries); err != nil {
+		t.Fatalf("writeNetrc: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Fatalf("expected mode 0600, got %o", perm)
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+
+	for _, want := range []string{"machine github.com", "login git", "password one", "machine gitlab.com", "password two"} {
+		if !strings.Contains(string(contents), want) {
+			t.Fatalf("expected netrc to contain %q, got:\n%s", want, contents)
+		}
+	}
+}
+
+func TestConfigurePrivateModulesNoopWithoutConfig(t *testing.T) {
+	runner := newFakeRunner()
+	c := Contributor{runner: runner, logger: fakeLogger{}}
+
+	if netrcPath, err := c.configurePrivateModules(Config{}); err != nil {
+		t.Fatalf("configurePrivateModules: %v", err)
+	} else if netrcPath != "" {
+		t.Fatalf("expected no netrc path when go.private is empty, got %q", netrcPath)
+	}
+
+	if runner.callCount() != 0 || len(runner.envs) != 0 {
+		t.Fatalf("expected no env vars set when go.private is empty, got envs=%v calls=%d", runner.envs, runner.callCount())
+	}
+}
+
+func TestConfigurePrivateModulesSetsGoprivateAndNetrc(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	bindingsRoot := t.TempDir()
+	bindingDir := filepath.Join(bindingsRoot, "gh-creds")
+	writeBindingFile(t, bindingDir, "machine", "github.com")
+	writeBindingFile(t, bindingDir, "login", "git")
+	writeBindingFile(t, bindingDir, "password", "s3cr3t")
+	t.Setenv(EnvServiceBindingsRoot, bindingsRoot)
+
+	runner := newFakeRunner()
+	c := Contributor{runner: runner, logger: fakeLogger{}}
+
+	config := Config{}
+	config.Go.Private = PrivateConfig{Hosts: []string{"github.com"}, Bindings: []string{"gh-creds"}}
+
+	netrcPath, err := c.configurePrivateModules(config)
+	if err != nil {
+		t.Fatalf("configurePrivateModules: %v", err)
+	}
+	if want := filepath.Join(home, ".netrc"); netrcPath != want {
+		t.Fatalf("expected netrc path %q, got %q", want, netrcPath)
+	}
+
+	if runner.env("GOPRIVATE") != "github.com" {
+		t.Fatalf("expected GOPRIVATE=github.com, got %q", runner.env("GOPRIVATE"))
+	}
+
+	contents, err := os.ReadFile(netrcPath)
+	if err != nil {
+		t.Fatalf("expected .netrc to be written: %v", err)
+	}
+	if !strings.Contains(string(contents), "machine github.com") {
+		t.Fatalf("expected .netrc to contain the configured machine, got:\n%s", contents)
+	}
+}
+
+// TestContributeGoModulesRemovesNetrcAfterInstall guards against the netrc
+// credentials written for `go install` outliving the build step that needed
+// them.
+func TestContributeGoModulesRemovesNetrcAfterInstall(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	bindingsRoot := t.TempDir()
+	bindingDir := filepath.Join(bindingsRoot, "gh-creds")
+	writeBindingFile(t, bindingDir, "machine", "github.com")
+	writeBindingFile(t, bindingDir, "login", "git")
+	writeBindingFile(t, bindingDir, "password", "s3cr3t")
+	t.Setenv(EnvServiceBindingsRoot, bindingsRoot)
+
+	appRoot := t.TempDir()
+	writeBindingFile(t, appRoot, "buildpack.yml", "go:\n  private:\n    bindings:\n    - gh-creds\n")
+
+	runner := newFakeRunner()
+	c := Contributor{
+		runner:     runner,
+		invoker:    NewInvoker(runner, t.TempDir()),
+		goModLayer: newTestLayer(t.TempDir()),
+		appRoot:    appRoot,
+		logger:     fakeLogger{},
+	}
+
+	if err := c.ContributeGoModules(newTestLayer(t.TempDir())); err != nil {
+		t.Fatalf("ContributeGoModules: %v", err)
+	}
+
+	netrcPath := filepath.Join(home, ".netrc")
+	if _, err := os.Stat(netrcPath); !os.IsNotExist(err) {
+		t.Fatalf("expected %s to be removed once go install finished, stat err: %v", netrcPath, err)
+	}
+}
+
+func TestConfigurePrivateModulesAppendsEnvHosts(t *testing.T) {
+	t.Setenv(EnvGoPrivateHosts, "example.com,internal.example.com")
+
+	runner := newFakeRunner()
+	c := Contributor{runner: runner, logger: fakeLogger{}}
+
+	if _, err := c.configurePrivateModules(Config{}); err != nil {
+		t.Fatalf("configurePrivateModules: %v", err)
+	}
+
+	if runner.env("GOPRIVATE") != "example.com,internal.example.com" {
+		t.Fatalf("expected hosts from %s, got %q", EnvGoPrivateHosts, runner.env("GOPRIVATE"))
+	}
+}
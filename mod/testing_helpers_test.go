@@ -0,0 +1,90 @@
+package mod
+
+import (
+	"sync"
+
+	baselayers "github.com/buildpack/libbuildpack/layers"
+	"github.com/cloudfoundry/libcfbuildpack/layers"
+)
+
+// newTestLayer builds a layers.Layer rooted at dir. layers.Layer embeds the
+// underlying libbuildpack layer anonymously, so Root isn't a direct field of
+// the outer type.
+func newTestLayer(dir string) layers.Layer {
+	return layers.Layer{Layer: baselayers.Layer{Root: dir}}
+}
+
+// fakeCall records one Run/RunWithOutput invocation for assertions in tests.
+type fakeCall struct {
+	bin  string
+	dir  string
+	args []string
+}
+
+// fakeRunner is a test double for Runner. Behavior is injected via runFunc /
+// outputFunc so each test can simulate the exact `go` output/error it needs.
+type fakeRunner struct {
+	mu       sync.Mutex
+	envs     map[string]string
+	calls    []fakeCall
+	runFunc  func(bin, dir string, quiet bool, args ...string) error
+	outputFunc func(bin, dir string, quiet bool, args ...string) (string, error)
+}
+
+func newFakeRunner() *fakeRunner {
+	return &fakeRunner{envs: map[string]string{}}
+}
+
+func (f *fakeRunner) Run(bin, dir string, quiet bool, args ...string) error {
+	if f.runFunc != nil {
+		f.recordCall(bin, dir, args)
+		return f.runFunc(bin, dir, quiet, args...)
+	}
+
+	_, err := f.RunWithOutput(bin, dir, quiet, args...)
+	return err
+}
+
+func (f *fakeRunner) RunWithOutput(bin, dir string, quiet bool, args ...string) (string, error) {
+	f.recordCall(bin, dir, args)
+
+	if f.outputFunc != nil {
+		return f.outputFunc(bin, dir, quiet, args...)
+	}
+
+	return "", nil
+}
+
+func (f *fakeRunner) SetEnv(name, value string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.envs == nil {
+		f.envs = map[string]string{}
+	}
+	f.envs[name] = value
+
+	return nil
+}
+
+func (f *fakeRunner) recordCall(bin, dir string, args []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, fakeCall{bin: bin, dir: dir, args: args})
+}
+
+func (f *fakeRunner) callCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.calls)
+}
+
+func (f *fakeRunner) env(name string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.envs[name]
+}
+
+type fakeLogger struct{}
+
+func (fakeLogger) Info(format string, args ...interface{}) {}
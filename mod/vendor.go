@@ -0,0 +1,142 @@
+package mod
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudfoundry/libcfbuildpack/helper"
+)
+
+const (
+	// EnvGoVendor overrides the `go.vendor` buildpack.yml setting.
+	EnvGoVendor = "BP_GO_VENDOR"
+
+	VendorOff   = "off"
+	VendorAuto  = "auto"
+	VendorForce = "force"
+
+	vendorWorkspace = "vendor-workspace"
+)
+
+func vendorMode(config Config) string {
+	if mode := os.Getenv(EnvGoVendor); mode != "" {
+		return mode
+	}
+
+	if config.Go.Vendor != "" {
+		return config.Go.Vendor
+	}
+
+	return VendorOff
+}
+
+// ensureVendor reconciles the configured `go.vendor` mode with the app's
+// vendor/ directory, running `go mod vendor` when needed, and reports whether
+// `-mod=vendor` should be passed to `go install`.
+func (c Contributor) ensureVendor(config Config) (bool, error) {
+	vendorPath := filepath.Join(c.appRoot, "vendor")
+
+	exists, err := helper.FileExists(vendorPath)
+	if err != nil {
+		return false, err
+	}
+
+	switch vendorMode(config) {
+	case VendorForce:
+		if err := c.runGoModVendor(vendorPath); err != nil {
+			return false, err
+		}
+		return true, nil
+
+	case VendorAuto:
+		if exists {
+			return true, nil
+		}
+		if modFlagRequestsVendor(config) {
+			if err := c.runGoModVendor(vendorPath); err != nil {
+				return false, err
+			}
+			return true, nil
+		}
+		return false, nil
+
+	default:
+		return exists, nil
+	}
+}
+
+// modFlagRequestsVendor reports whether the user has already asked for
+// `-mod=vendor` via GOFLAGS, either in the environment or through go.build.env.
+func modFlagRequestsVendor(config Config) bool {
+	if strings.Contains(os.Getenv("GOFLAGS"), "-mod=vendor") {
+		return true
+	}
+
+	return strings.Contains(config.Go.Build.Env["GOFLAGS"], "-mod=vendor")
+}
+
+// runGoModVendor runs `go mod vendor` in a workspace under the go-mod layer,
+// rather than the app directory, so the app root stays pristine, then copies
+// the resulting vendor/ tree back so `go install` picks it up.
+func (c Contributor) runGoModVendor(vendorPath string) error {
+	workspace := filepath.Join(c.goModLayer.Root, vendorWorkspace)
+
+	if err := os.RemoveAll(workspace); err != nil {
+		return err
+	}
+
+	if err := helper.CopyDirectory(c.appRoot, workspace); err != nil {
+		return err
+	}
+
+	c.logger.Info("Running `go mod vendor`")
+	if err := c.invoker.Run(context.Background(), "go", workspace, false, "mod", "vendor"); err != nil {
+		return err
+	}
+
+	return helper.CopyDirectory(filepath.Join(workspace, "vendor"), vendorPath)
+}
+
+// verifyModules runs `go mod verify` and turns a mismatch into a ModVerifyError
+// naming the offending modules, rather than surfacing the raw go output.
+func (c Contributor) verifyModules() error {
+	c.logger.Info("Verifying go.sum")
+
+	output, err := c.invoker.RunWithOutput(context.Background(), "go", c.appRoot, false, "mod", "verify")
+	if err != nil {
+		return &ModVerifyError{Modules: parseModVerifyFailures(output), Output: output}
+	}
+
+	return nil
+}
+
+// ModVerifyError reports the modules whose content does not match the hash
+// recorded in go.sum.
+type ModVerifyError struct {
+	Modules []string
+	Output  string
+}
+
+func (e *ModVerifyError) Error() string {
+	if len(e.Modules) == 0 {
+		return fmt.Sprintf("go.sum verification failed:\n%s", e.Output)
+	}
+
+	return fmt.Sprintf("go.sum verification failed for module(s) %s:\n%s", strings.Join(e.Modules, ", "), e.Output)
+}
+
+func parseModVerifyFailures(output string) []string {
+	var modules []string
+
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && strings.Contains(line, "checksum mismatch") {
+			modules = append(modules, strings.TrimSuffix(fields[0], ":"))
+		}
+	}
+
+	return modules
+}
@@ -0,0 +1,179 @@
+package mod
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var errExitStatus1 = errors.New("exit status 1")
+
+func TestVendorModePrecedence(t *testing.T) {
+	if got := vendorMode(Config{}); got != VendorOff {
+		t.Fatalf("expected default mode %q, got %q", VendorOff, got)
+	}
+
+	var yamlConfig Config
+	yamlConfig.Go.Vendor = VendorForce
+	if got := vendorMode(yamlConfig); got != VendorForce {
+		t.Fatalf("expected yaml mode %q, got %q", VendorForce, got)
+	}
+
+	t.Setenv(EnvGoVendor, VendorAuto)
+	if got := vendorMode(yamlConfig); got != VendorAuto {
+		t.Fatalf("expected env to win over yaml, got %q", got)
+	}
+}
+
+func TestEnsureVendorOffUsesExistingDirOnly(t *testing.T) {
+	appRoot := t.TempDir()
+	runner := newFakeRunner()
+	c := Contributor{appRoot: appRoot, runner: runner, invoker: NewInvoker(runner, appRoot), logger: fakeLogger{}}
+
+	vendored, err := c.ensureVendor(Config{})
+	if err != nil {
+		t.Fatalf("ensureVendor: %v", err)
+	}
+	if vendored {
+		t.Fatalf("expected no vendoring when vendor/ is absent and mode is off")
+	}
+	if runner.callCount() != 0 {
+		t.Fatalf("expected `go mod vendor` not to run in off mode")
+	}
+
+	if err := os.MkdirAll(filepath.Join(appRoot, "vendor"), 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+
+	vendored, err = c.ensureVendor(Config{})
+	if err != nil {
+		t.Fatalf("ensureVendor: %v", err)
+	}
+	if !vendored {
+		t.Fatalf("expected -mod=vendor once vendor/ exists")
+	}
+}
+
+// fakeRunnerThatVendors simulates `go mod vendor` by creating a vendor/
+// directory with a marker file inside whatever dir it was invoked in.
+func fakeRunnerThatVendors() *fakeRunner {
+	runner := newFakeRunner()
+	runner.outputFunc = func(bin, dir string, quiet bool, args ...string) (string, error) {
+		depDir := filepath.Join(dir, "vendor", "example.com", "dep")
+		if err := os.MkdirAll(depDir, 0755); err != nil {
+			return "", err
+		}
+		return "", os.WriteFile(filepath.Join(depDir, "dep.go"), []byte("package dep"), 0644)
+	}
+	return runner
+}
+
+func TestEnsureVendorForceAlwaysVendors(t *testing.T) {
+	appRoot := t.TempDir()
+	goModLayer := t.TempDir()
+
+	runner := fakeRunnerThatVendors()
+	c := Contributor{
+		appRoot:    appRoot,
+		goModLayer: newTestLayer(goModLayer),
+		runner:     runner,
+		invoker:    NewInvoker(runner, goModLayer),
+		logger:     fakeLogger{},
+	}
+
+	var config Config
+	config.Go.Vendor = VendorForce
+
+	vendored, err := c.ensureVendor(config)
+	if err != nil {
+		t.Fatalf("ensureVendor: %v", err)
+	}
+	if !vendored {
+		t.Fatalf("expected force mode to report vendored=true")
+	}
+
+	if _, err := os.Stat(filepath.Join(appRoot, "vendor", "example.com", "dep")); err != nil {
+		t.Fatalf("expected vendor/ to be copied back into the app root: %v", err)
+	}
+}
+
+func TestEnsureVendorAutoRunsOnlyWhenModFlagRequestsIt(t *testing.T) {
+	appRoot := t.TempDir()
+	goModLayer := t.TempDir()
+
+	runner := fakeRunnerThatVendors()
+	c := Contributor{
+		appRoot:    appRoot,
+		goModLayer: newTestLayer(goModLayer),
+		runner:     runner,
+		invoker:    NewInvoker(runner, goModLayer),
+		logger:     fakeLogger{},
+	}
+
+	var config Config
+	config.Go.Vendor = VendorAuto
+
+	vendored, err := c.ensureVendor(config)
+	if err != nil {
+		t.Fatalf("ensureVendor: %v", err)
+	}
+	if vendored || runner.callCount() != 0 {
+		t.Fatalf("expected auto mode to do nothing without vendor/ or -mod=vendor")
+	}
+
+	t.Setenv("GOFLAGS", "-mod=vendor")
+
+	vendored, err = c.ensureVendor(config)
+	if err != nil {
+		t.Fatalf("ensureVendor: %v", err)
+	}
+	if !vendored || runner.callCount() != 1 {
+		t.Fatalf("expected auto mode to vendor once GOFLAGS requests -mod=vendor")
+	}
+}
+
+func TestVerifyModulesSuccess(t *testing.T) {
+	runner := newFakeRunner()
+	runner.outputFunc = func(bin, dir string, quiet bool, args ...string) (string, error) {
+		return "all modules verified", nil
+	}
+
+	c := Contributor{appRoot: "/app", runner: runner, invoker: NewInvoker(runner, "/gopath"), logger: fakeLogger{}}
+
+	if err := c.verifyModules(); err != nil {
+		t.Fatalf("expected verifyModules to succeed, got %v", err)
+	}
+}
+
+func TestVerifyModulesTamperedGoSum(t *testing.T) {
+	tamperedOutput := "example.com/foo@v1.2.3: checksum mismatch\n\tdownloaded: h1:aaaa=\n\tgo.sum:     h1:bbbb="
+
+	runner := newFakeRunner()
+	runner.outputFunc = func(bin, dir string, quiet bool, args ...string) (string, error) {
+		return tamperedOutput, errExitStatus1
+	}
+
+	c := Contributor{appRoot: "/app", runner: runner, invoker: NewInvoker(runner, "/gopath"), logger: fakeLogger{}}
+
+	err := c.verifyModules()
+	if err == nil {
+		t.Fatalf("expected verifyModules to fail on checksum mismatch")
+	}
+
+	verifyErr, ok := err.(*ModVerifyError)
+	if !ok {
+		t.Fatalf("expected *ModVerifyError, got %T: %v", err, err)
+	}
+
+	if len(verifyErr.Modules) != 1 || verifyErr.Modules[0] != "example.com/foo@v1.2.3" {
+		t.Fatalf("expected offending module to be reported, got %v", verifyErr.Modules)
+	}
+}
+
+func TestParseModVerifyFailuresIgnoresCleanOutput(t *testing.T) {
+	modules := parseModVerifyFailures("example.com/foo@v1.0.0: OK\nexample.com/bar@v2.0.0: OK")
+	if len(modules) != 0 {
+		t.Fatalf("expected no offending modules, got %v", modules)
+	}
+}